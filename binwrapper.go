@@ -4,8 +4,12 @@ package binwrapper
 
 import (
 	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"hash"
 	"io"
 	"io/ioutil"
 	"net/http"
@@ -15,24 +19,63 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"sync"
 	"time"
-
-	"github.com/mholt/archiver"
 )
 
+// ChecksumError is returned when a downloaded file's digest doesn't match the declared one
+type ChecksumError struct {
+	Algorithm string
+	Expected  string
+	Actual    string
+}
+
+func (e *ChecksumError) Error() string {
+	return fmt.Sprintf("%s checksum mismatch: expected %s, got %s", e.Algorithm, e.Expected, e.Actual)
+}
+
+// SignatureError is returned when a downloaded file fails minisign signature verification
+type SignatureError struct {
+	File string
+}
+
+func (e *SignatureError) Error() string {
+	return "signature verification failed for " + e.File
+}
+
 // Src defines executable source
 type Src struct {
-	url      string
-	os       string
-	arch     string
-	execPath string
-}
+	url            string
+	mirrors        []string
+	os             string
+	arch           string
+	execPath       string
+	version        string
+	sha256         string
+	sha512         string
+	minisignPubKey string
+	signatureURL   string
+}
+
+// InstallScope controls where BinWrapper resolves its install directory to when Dest is unset.
+type InstallScope int
+
+const (
+	// Local resolves to the current directory, matching BinWrapper's historical behaviour.
+	Local InstallScope = iota
+	// UserCache resolves to os.UserCacheDir()/<name>/<version>, shared across programs on the same host.
+	UserCache
+	// System resolves to a machine-wide install directory (ProgramData on Windows, /usr/local/share elsewhere).
+	System
+)
 
 // BinWrapper wraps executable and provides convenient methods to interact with
 type BinWrapper struct {
 	src      []*Src
 	dest     string
 	execPath string
+	name     string
+	scope    InstallScope
 	strip    int
 	output   []byte
 	autoExe  bool
@@ -42,11 +85,19 @@ type BinWrapper struct {
 	stdIn        io.Reader
 	stdOutWriter io.Writer
 
-	args    []string
-	env     []string
-	debug   bool
-	cmd     *exec.Cmd
-	timeout time.Duration
+	args         []string
+	env          []string
+	debug        bool
+	cmd          *exec.Cmd
+	ctx          context.Context
+	cancel       context.CancelFunc
+	timeout      time.Duration
+	progressFunc func(downloaded, total int64)
+	pipeErr      error
+
+	version    string
+	versionURL string
+	updateMu   sync.Mutex
 }
 
 // NewSrc creates new Src instance
@@ -60,6 +111,20 @@ func (s *Src) URL(value string) *Src {
 	return s
 }
 
+// Mirrors adds additional urls to try, in order, if URL fails to download.
+func (s *Src) Mirrors(values ...string) *Src {
+	s.mirrors = append(s.mirrors, values...)
+	return s
+}
+
+func (s *Src) urls() []string {
+	if s.url == "" {
+		return s.mirrors
+	}
+
+	return append([]string{s.url}, s.mirrors...)
+}
+
 // Os tie the source to a specific OS. Possible values are same as runtime.GOOS
 func (s *Src) Os(value string) *Src {
 	s.os = value
@@ -78,6 +143,41 @@ func (s *Src) ExecPath(value string) *Src {
 	return s
 }
 
+// Sha256 sets the expected sha256 checksum (hex-encoded) of the downloaded file.
+// download() aborts with a *ChecksumError if the computed digest doesn't match.
+func (s *Src) Sha256(value string) *Src {
+	s.sha256 = value
+	return s
+}
+
+// Sha512 sets the expected sha512 checksum (hex-encoded) of the downloaded file.
+// download() aborts with a *ChecksumError if the computed digest doesn't match.
+func (s *Src) Sha512(value string) *Src {
+	s.sha512 = value
+	return s
+}
+
+// MinisignPubKey sets the minisign public key used to verify the downloaded file's signature.
+// Must be used together with Signature.
+func (s *Src) MinisignPubKey(value string) *Src {
+	s.minisignPubKey = value
+	return s
+}
+
+// Signature sets a url pointing to the minisign signature of the file to download.
+// Must be used together with MinisignPubKey.
+func (s *Src) Signature(value string) *Src {
+	s.signatureURL = value
+	return s
+}
+
+// Version records the release version this Src provides. The value is only stored: downloads,
+// CheckUpdate and SelfUpdate do not read it, and it does not select a manifest entry.
+func (s *Src) Version(value string) *Src {
+	s.version = value
+	return s
+}
+
 // NewBinWrapper creates BinWrapper instance
 func NewBinWrapper() *BinWrapper {
 	return &BinWrapper{}
@@ -101,6 +201,21 @@ func (b *BinWrapper) Dest(dest string) *BinWrapper {
 	return b
 }
 
+// Name sets the tool name used to namespace the install directory when Scope is UserCache or
+// System. Defaults to ExecPath's base name, without extension, when unset.
+func (b *BinWrapper) Name(value string) *BinWrapper {
+	b.name = value
+	return b
+}
+
+// Scope controls where Dest("") resolves to: Local (the default, current directory), UserCache
+// (os.UserCacheDir()/<name>/<version>) or System (a machine-wide install directory). This lets
+// multiple Go programs on the same host share a single cached copy of a large binary.
+func (b *BinWrapper) Scope(scope InstallScope) *BinWrapper {
+	b.scope = scope
+	return b
+}
+
 // ExecPath define a file to use as the binary
 func (b *BinWrapper) ExecPath(execPath string) *BinWrapper {
 
@@ -134,6 +249,26 @@ func (b *BinWrapper) Strip(value int) *BinWrapper {
 	return b
 }
 
+// ProgressFunc sets a callback invoked while a source file is being downloaded,
+// reporting the number of bytes downloaded so far and the total size (0 if unknown).
+func (b *BinWrapper) ProgressFunc(f func(downloaded, total int64)) *BinWrapper {
+	b.progressFunc = f
+	return b
+}
+
+// Version sets the currently installed version, used by CheckUpdate and SelfUpdate to detect
+// when a newer build is published.
+func (b *BinWrapper) Version(current string) *BinWrapper {
+	b.version = current
+	return b
+}
+
+// VersionURL sets the url of a JSON version manifest consulted by CheckUpdate and SelfUpdate.
+func (b *BinWrapper) VersionURL(value string) *BinWrapper {
+	b.versionURL = value
+	return b
+}
+
 // Arg adds command line argument to run the binary with.
 func (b *BinWrapper) Arg(name string, values ...string) *BinWrapper {
 	values = append([]string{name}, values...)
@@ -160,6 +295,10 @@ func (b *BinWrapper) Path() string {
 		b.ExecPath(src.execPath)
 	}
 
+	if err := b.resolveScopedDest(); err != nil {
+		fmt.Printf("Unable to resolve install location: %v\n", err)
+	}
+
 	if b.dest == "." {
 		return b.dest + string(filepath.Separator) + b.execPath
 	}
@@ -167,6 +306,84 @@ func (b *BinWrapper) Path() string {
 	return filepath.Join(b.dest, b.execPath)
 }
 
+// resolveScopedDest fills in dest from Scope/Name/Version when Dest was left unset, memoizing the
+// result into b.dest. Local scope is left alone, falling back to the existing "." default.
+func (b *BinWrapper) resolveScopedDest() error {
+	if b.dest != "" || b.scope == Local {
+		return nil
+	}
+
+	var base string
+	var err error
+
+	switch b.scope {
+	case UserCache:
+		base, err = os.UserCacheDir()
+	case System:
+		base, err = systemInstallDir()
+	}
+
+	if err != nil {
+		return err
+	}
+
+	name := b.name
+
+	if name == "" {
+		name = strings.TrimSuffix(filepath.Base(b.execPath), filepath.Ext(b.execPath))
+	}
+
+	version := b.version
+
+	if version == "" {
+		version = "latest"
+	}
+
+	b.dest = filepath.Join(base, name, version)
+
+	return nil
+}
+
+func systemInstallDir() (string, error) {
+	if runtime.GOOS == "windows" {
+		dir := os.Getenv("ProgramData")
+
+		if dir == "" {
+			return "", errors.New("ProgramData is not set")
+		}
+
+		return dir, nil
+	}
+
+	return "/usr/local/share", nil
+}
+
+// FindInPath looks up the binary on the system PATH first, honoring AutoExe's .exe suffix on
+// Windows, and only falls back to downloading it via Src if it isn't already installed.
+func (b *BinWrapper) FindInPath() (string, error) {
+	name := b.execPath
+
+	if name == "" {
+		if src := osFilterObj(b.src); src != nil {
+			name = src.execPath
+		}
+	}
+
+	if b.autoExe && runtime.GOOS == "windows" && strings.ToLower(filepath.Ext(name)) != ".exe" {
+		name += ".exe"
+	}
+
+	if path, err := exec.LookPath(name); err == nil {
+		return path, nil
+	}
+
+	if err := b.findExisting(); err != nil {
+		return "", err
+	}
+
+	return b.Path(), nil
+}
+
 // StdIn sets reader to read executable's stdin from
 func (b *BinWrapper) StdIn(reader io.Reader) *BinWrapper {
 	b.stdIn = reader
@@ -211,13 +428,96 @@ func (b *BinWrapper) Reset() *BinWrapper {
 	b.stdOutWriter = nil
 	b.env = nil
 	b.cmd = nil
+	b.ctx = nil
+	b.cancel = nil
+	b.pipeErr = nil
 	return b
 }
 
-// Run runs the binary with provided arg list.
-// Arg list is appended to args set through Arg method
-// Returns context.DeadlineExceeded in case of timeout
-func (b *BinWrapper) Run(arg ...string) error {
+// prepareCmd builds the underlying exec.Cmd, without starting it, so StdoutPipe/StderrPipe/
+// StdinPipe can be wired up beforehand the same way they are with exec.Cmd.
+func (b *BinWrapper) prepareCmd() error {
+	if b.cmd != nil {
+		return nil
+	}
+
+	if b.timeout > 0 {
+		b.ctx, b.cancel = context.WithTimeout(context.Background(), b.timeout)
+	} else {
+		b.ctx = context.Background()
+		b.cancel = func() {}
+	}
+
+	b.cmd = exec.CommandContext(b.ctx, b.Path(), b.args...)
+
+	if b.env != nil {
+		b.cmd.Env = b.env
+	}
+
+	if b.stdIn != nil {
+		b.cmd.Stdin = b.stdIn
+	}
+
+	if b.stdOutWriter != nil {
+		b.cmd.Stdout = b.stdOutWriter
+	}
+
+	return nil
+}
+
+// StdoutPipe returns a pipe connected to the binary's stdout, following exec.Cmd's convention:
+// it must be called before Start.
+func (b *BinWrapper) StdoutPipe() (io.ReadCloser, error) {
+	if err := b.prepareCmd(); err != nil {
+		return nil, err
+	}
+
+	return b.cmd.StdoutPipe()
+}
+
+// StderrPipe returns a pipe connected to the binary's stderr, following exec.Cmd's convention:
+// it must be called before Start.
+func (b *BinWrapper) StderrPipe() (io.ReadCloser, error) {
+	if err := b.prepareCmd(); err != nil {
+		return nil, err
+	}
+
+	return b.cmd.StderrPipe()
+}
+
+// StdinPipe returns a pipe connected to the binary's stdin, following exec.Cmd's convention:
+// it must be called before Start.
+func (b *BinWrapper) StdinPipe() (io.WriteCloser, error) {
+	if err := b.prepareCmd(); err != nil {
+		return nil, err
+	}
+
+	return b.cmd.StdinPipe()
+}
+
+// Pipe wires this binary's stdout into next's stdin, so the two can be run concurrently as a
+// pipeline (e.g. cwebp output streamed straight into ffmpeg). Returns next for chaining.
+// If wiring the pipe fails, next's Start/Run return the failure instead of running unconnected.
+func (b *BinWrapper) Pipe(next *BinWrapper) *BinWrapper {
+	stdout, err := b.StdoutPipe()
+
+	if err != nil {
+		next.pipeErr = fmt.Errorf("unable to pipe into %s: %w", next.Path(), err)
+		return next
+	}
+
+	next.StdIn(stdout)
+	return next
+}
+
+// Start starts the binary with the provided arg list without waiting for it to complete.
+// Arg list is appended to args set through Arg method. Callers that need streaming access to
+// stdin/stdout/stderr should obtain the pipes before calling Start, then call Wait when done.
+func (b *BinWrapper) Start(arg ...string) error {
+	if b.pipeErr != nil {
+		return b.pipeErr
+	}
+
 	if b.src != nil && len(b.src) > 0 {
 		err := b.findExisting()
 
@@ -226,41 +526,74 @@ func (b *BinWrapper) Run(arg ...string) error {
 		}
 	}
 
-	arg = append(b.args, arg...)
+	if err := b.prepareCmd(); err != nil {
+		return err
+	}
+
+	b.cmd.Args = append(b.cmd.Args, arg...)
 
 	if b.debug {
-		fmt.Println("BinWrapper.Run: " + b.Path() + " " + strings.Join(arg, " "))
+		fmt.Println("BinWrapper.Start: " + strings.Join(b.cmd.Args, " "))
 	}
 
-	var ctx context.Context
-	var cancel context.CancelFunc
+	return b.cmd.Start()
+}
+
+// Wait waits for a command started with Start to exit.
+// Returns context.DeadlineExceeded in case of timeout.
+func (b *BinWrapper) Wait() error {
+	if b.cmd == nil {
+		return errors.New("Start was not called")
+	}
 
-	if b.timeout > 0 {
-		ctx, cancel = context.WithTimeout(context.Background(), b.timeout)
-	} else {
-		ctx = context.Background()
-		cancel = func() {
+	err := b.cmd.Wait()
+	ctx := b.ctx
 
-		}
+	if b.cancel != nil {
+		b.cancel()
 	}
 
-	defer cancel()
+	// Clear cmd so a subsequent Start/Run builds a fresh exec.Cmd rather than reusing this one
+	b.cmd = nil
+	b.ctx = nil
+	b.cancel = nil
 
-	b.cmd = exec.CommandContext(ctx, b.Path(), arg...)
+	if ctx != nil && ctx.Err() == context.DeadlineExceeded {
+		return context.DeadlineExceeded
+	}
 
-	if b.env != nil {
-		b.cmd.Env = b.env
+	return err
+}
+
+// Run runs the binary with provided arg list, buffering its stdout/stderr into memory.
+// Arg list is appended to args set through Arg method
+// Returns context.DeadlineExceeded in case of timeout
+func (b *BinWrapper) Run(arg ...string) error {
+	if b.pipeErr != nil {
+		return b.pipeErr
 	}
 
-	if b.stdIn != nil {
-		b.cmd.Stdin = b.stdIn
+	if b.src != nil && len(b.src) > 0 {
+		err := b.findExisting()
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := b.prepareCmd(); err != nil {
+		return err
+	}
+
+	b.cmd.Args = append(b.cmd.Args, arg...)
+
+	if b.debug {
+		fmt.Println("BinWrapper.Run: " + strings.Join(b.cmd.Args, " "))
 	}
 
 	var stdout io.Reader
 
-	if b.stdOutWriter != nil {
-		b.cmd.Stdout = b.stdOutWriter
-	} else {
+	if b.stdOutWriter == nil {
 		stdout, _ = b.cmd.StdoutPipe()
 	}
 
@@ -277,13 +610,8 @@ func (b *BinWrapper) Run(arg ...string) error {
 	}
 
 	b.stdErr, _ = ioutil.ReadAll(stderr)
-	err = b.cmd.Wait()
-
-	if ctx.Err() == context.DeadlineExceeded {
-		return context.DeadlineExceeded
-	}
 
-	return err
+	return b.Wait()
 }
 
 // Kill terminates the process
@@ -315,12 +643,20 @@ func (b *BinWrapper) download() error {
 		return errors.New("No binary found matching your system. It's probably not supported")
 	}
 
-	file, err := b.downloadFile(src.url)
+	file, err := b.downloadFile(src)
 
 	if err != nil {
 		return err
 	}
 
+	if src.signatureURL != "" {
+		err = b.verifySignature(file, src)
+
+		if err != nil {
+			return err
+		}
+	}
+
 	fmt.Printf("%s downloaded. Trying to extract...\n", file)
 
 	err = b.extractFile(file)
@@ -339,117 +675,279 @@ func (b *BinWrapper) download() error {
 func (b *BinWrapper) extractFile(file string) error {
 
 	defer os.Remove(file)
-	err := archiver.Unarchive(file, b.dest)
+
+	extractor := findExtractor(filepath.Base(file))
+
+	if extractor == nil {
+		return fmt.Errorf("%s is not an archive or have unsupported archive format", file)
+	}
+
+	err := extractor.Extract(file, b.dest, b.strip)
 
 	if err != nil {
 		fmt.Printf("%s is not an archive or have unsupported archive format\n", file)
 		return err
 	}
 
-	if b.strip == 0 {
-		return nil
+	return nil
+}
+
+const maxDownloadAttempts = 3
+const initialRetryBackoff = time.Second
+
+func (b *BinWrapper) downloadFile(src *Src) (string, error) {
+
+	if b.dest == "" {
+		b.dest = "."
 	}
 
-	return b.stripDir()
-}
+	err := os.MkdirAll(b.dest, 0755)
 
-func (b *BinWrapper) stripDir() error {
-	dir := b.dest
+	if err != nil {
+		return "", err
+	}
 
-	var dirsToRemove []string
+	urls := src.urls()
 
-	for i := 0; i < b.strip; i++ {
-		files, err := ioutil.ReadDir(dir)
+	if len(urls) == 0 {
+		return "", errors.New("No url to download")
+	}
 
-		if err != nil {
-			return err
-		}
+	fileName := fileNameFromURL(b.dest, urls[0])
+	partFileName := fileName + ".part"
+
+	backoff := initialRetryBackoff
+	var lastErr error
+	var partURL string
 
-		for _, v := range files {
-			if v.IsDir() {
+	for attempt := 0; attempt < maxDownloadAttempts; attempt++ {
+		for _, u := range urls {
+			// Only resume the .part file if the same URL wrote it; a different mirror may serve
+			// different bytes at the same offset, which would silently corrupt the download.
+			resume := u == partURL
+			partURL = u
 
-				if dir != b.dest {
-					dirsToRemove = append(dirsToRemove, dir)
-				}
+			lastErr = b.downloadToFile(u, partFileName, resume)
 
-				dir = filepath.Join(dir, v.Name())
+			if lastErr == nil {
 				break
 			}
-		}
-	}
-
-	files, err := ioutil.ReadDir(dir)
 
-	if err != nil {
-		return err
-	}
+			fmt.Printf("Failed to download %s: %v\n", u, lastErr)
+		}
 
-	for _, v := range files {
-		err := os.Rename(filepath.Join(dir, v.Name()), filepath.Join(b.dest, v.Name()))
+		if lastErr == nil {
+			break
+		}
 
-		if err != nil {
-			return err
+		if attempt < maxDownloadAttempts-1 {
+			time.Sleep(backoff)
+			backoff *= 2
 		}
 	}
 
-	for _, v := range dirsToRemove {
-		os.RemoveAll(v)
+	if lastErr != nil {
+		return "", lastErr
 	}
 
-	return nil
-}
-
-func (b *BinWrapper) downloadFile(value string) (string, error) {
+	err = os.Rename(partFileName, fileName)
 
-	if b.dest == "" {
-		b.dest = "."
+	if err != nil {
+		return "", err
 	}
 
-	err := os.MkdirAll(b.dest, 0755)
+	err = verifyChecksum(fileName, src)
 
 	if err != nil {
 		return "", err
 	}
 
+	return fileName, nil
+}
+
+func fileNameFromURL(dest string, value string) string {
 	fileURL, err := url.Parse(value)
 
 	if err != nil {
-		return "", err
+		return filepath.Join(dest, value)
 	}
 
-	path := fileURL.Path
+	segments := strings.Split(fileURL.Path, "/")
+	return filepath.Join(dest, segments[len(segments)-1])
+}
+
+// downloadToFile downloads value into partFileName. When resume is true and partFileName already
+// exists, it's assumed to hold a previous partial download of this same value and is continued via
+// an HTTP Range request; otherwise any existing content is discarded and the download starts fresh.
+func (b *BinWrapper) downloadToFile(value string, partFileName string, resume bool) error {
+	var offset int64
+	flags := os.O_RDWR | os.O_CREATE
+
+	if resume {
+		if info, err := os.Stat(partFileName); err == nil {
+			offset = info.Size()
+		}
+
+		flags |= os.O_APPEND
+	} else {
+		flags |= os.O_TRUNC
+	}
 
-	segments := strings.Split(path, "/")
-	fileName := segments[len(segments)-1]
-	fileName = filepath.Join(b.dest, fileName)
-	file, err := os.OpenFile(fileName, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0755)
+	file, err := os.OpenFile(partFileName, flags, 0755)
 
 	if err != nil {
-		return "", err
+		return err
 	}
 
 	defer file.Close()
 
-	check := http.Client{
+	req, err := http.NewRequest("GET", value, nil)
+
+	if err != nil {
+		return err
+	}
+
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	client := http.Client{
 		CheckRedirect: func(r *http.Request, via []*http.Request) error {
 			r.URL.Opaque = r.URL.Path
 			return nil
 		},
 	}
 
-	resp, err := check.Get(value)
+	resp, err := client.Do(req)
 
 	if err != nil {
-		return "", err
+		return err
 	}
 
 	defer resp.Body.Close()
 
 	if !(resp.StatusCode >= 200 && resp.StatusCode < 400) {
-		return "", errors.New("Unable to download " + value)
+		return errors.New("Unable to download " + value)
+	}
+
+	if resp.StatusCode != http.StatusPartialContent {
+		offset = 0
+
+		if err := file.Truncate(0); err != nil {
+			return err
+		}
+
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+	}
+
+	var dst io.Writer = file
+
+	if b.progressFunc != nil {
+		var total int64
+
+		if resp.ContentLength >= 0 {
+			total = offset + resp.ContentLength
+		}
+
+		dst = io.MultiWriter(file, &progressWriter{downloaded: offset, total: total, progressFunc: b.progressFunc})
+	}
+
+	_, err = io.Copy(dst, resp.Body)
+
+	return err
+}
+
+// progressWriter reports cumulative bytes written through to ProgressFunc
+type progressWriter struct {
+	downloaded   int64
+	total        int64
+	progressFunc func(downloaded, total int64)
+}
+
+func (w *progressWriter) Write(data []byte) (int, error) {
+	w.downloaded += int64(len(data))
+	w.progressFunc(w.downloaded, w.total)
+	return len(data), nil
+}
+
+func verifyChecksum(fileName string, src *Src) error {
+	algorithm, expected, hasher := checksumHasher(src)
+
+	if hasher == nil {
+		return nil
+	}
+
+	file, err := os.Open(fileName)
+
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	if _, err := io.Copy(hasher, file); err != nil {
+		return err
 	}
 
-	_, err = io.Copy(file, resp.Body)
+	actual := hex.EncodeToString(hasher.Sum(nil))
 
-	return fileName, err
+	if !strings.EqualFold(actual, expected) {
+		return &ChecksumError{Algorithm: algorithm, Expected: expected, Actual: actual}
+	}
+
+	return nil
+}
+
+func checksumHasher(src *Src) (string, string, hash.Hash) {
+	if src.sha256 != "" {
+		return "sha256", src.sha256, sha256.New()
+	}
+
+	if src.sha512 != "" {
+		return "sha512", src.sha512, sha512.New()
+	}
+
+	return "", "", nil
+}
+
+func (b *BinWrapper) verifySignature(file string, src *Src) error {
+	sigFile, err := b.downloadFile(&Src{url: src.signatureURL})
+
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(sigFile)
+
+	publicKey, err := parseMinisignPublicKey(src.minisignPubKey)
+
+	if err != nil {
+		return err
+	}
+
+	sigData, err := ioutil.ReadFile(sigFile)
+
+	if err != nil {
+		return err
+	}
+
+	signature, err := parseMinisignSignature(string(sigData))
+
+	if err != nil {
+		return err
+	}
+
+	data, err := ioutil.ReadFile(file)
+
+	if err != nil {
+		return err
+	}
+
+	if !publicKey.verify(data, signature) {
+		return &SignatureError{File: file}
+	}
+
+	return nil
 }
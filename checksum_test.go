@@ -0,0 +1,79 @@
+package binwrapper
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChecksumHasher(t *testing.T) {
+	algorithm, expected, hasher := checksumHasher(NewSrc().Sha256("abc"))
+	assert.Equal(t, "sha256", algorithm)
+	assert.Equal(t, "abc", expected)
+	assert.NotNil(t, hasher)
+
+	algorithm, expected, hasher = checksumHasher(NewSrc().Sha512("def"))
+	assert.Equal(t, "sha512", algorithm)
+	assert.Equal(t, "def", expected)
+	assert.NotNil(t, hasher)
+
+	algorithm, expected, hasher = checksumHasher(NewSrc())
+	assert.Equal(t, "", algorithm)
+	assert.Equal(t, "", expected)
+	assert.Nil(t, hasher)
+}
+
+func TestVerifyChecksumNoChecksumConfigured(t *testing.T) {
+	dir := t.TempDir()
+	fileName := filepath.Join(dir, "file.bin")
+	assert.NoError(t, ioutil.WriteFile(fileName, []byte("hello"), 0644))
+
+	err := verifyChecksum(fileName, NewSrc())
+	assert.NoError(t, err)
+}
+
+func TestVerifyChecksumSuccessSha256(t *testing.T) {
+	dir := t.TempDir()
+	fileName := filepath.Join(dir, "file.bin")
+	content := []byte("hello world")
+	assert.NoError(t, ioutil.WriteFile(fileName, content, 0644))
+
+	sum := sha256.Sum256(content)
+	src := NewSrc().Sha256(hex.EncodeToString(sum[:]))
+
+	err := verifyChecksum(fileName, src)
+	assert.NoError(t, err)
+}
+
+func TestVerifyChecksumSuccessSha512(t *testing.T) {
+	dir := t.TempDir()
+	fileName := filepath.Join(dir, "file.bin")
+	content := []byte("hello world")
+	assert.NoError(t, ioutil.WriteFile(fileName, content, 0644))
+
+	sum := sha512.Sum512(content)
+	src := NewSrc().Sha512(hex.EncodeToString(sum[:]))
+
+	err := verifyChecksum(fileName, src)
+	assert.NoError(t, err)
+}
+
+func TestVerifyChecksumMismatch(t *testing.T) {
+	dir := t.TempDir()
+	fileName := filepath.Join(dir, "file.bin")
+	assert.NoError(t, ioutil.WriteFile(fileName, []byte("hello world"), 0644))
+
+	src := NewSrc().Sha256(hex.EncodeToString(make([]byte, sha256.Size)))
+
+	err := verifyChecksum(fileName, src)
+	assert.Error(t, err)
+
+	checksumErr, ok := err.(*ChecksumError)
+	assert.True(t, ok)
+	assert.Equal(t, "sha256", checksumErr.Algorithm)
+}
@@ -0,0 +1,40 @@
+package binwrapper
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileNameFromURL(t *testing.T) {
+	assert.Equal(t, filepath.Join("dest", "file.tar.gz"), fileNameFromURL("dest", "https://example.com/path/file.tar.gz"))
+	assert.Equal(t, filepath.Join("dest", "file.tar.gz"), fileNameFromURL("dest", "https://example.com/path/file.tar.gz?query=1"))
+}
+
+func TestDownloadToFileClampsUnknownContentLengthToZero(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.(http.Flusher).Flush()
+		w.Write([]byte("hello"))
+	}))
+	defer server.Close()
+
+	var reportedTotals []int64
+
+	b := NewBinWrapper().ProgressFunc(func(downloaded, total int64) {
+		reportedTotals = append(reportedTotals, total)
+	})
+
+	dir := t.TempDir()
+	partFileName := filepath.Join(dir, "file.part")
+
+	err := b.downloadToFile(server.URL, partFileName, false)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, reportedTotals)
+
+	for _, total := range reportedTotals {
+		assert.Equal(t, int64(0), total)
+	}
+}
@@ -0,0 +1,449 @@
+package binwrapper
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/bzip2"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/mholt/archiver"
+	"github.com/ulikunitz/xz"
+)
+
+// Extractor knows how to unpack a specific archive format into a destination directory.
+type Extractor interface {
+	// Match reports whether this Extractor handles filename, judging solely by its name.
+	Match(filename string) bool
+	// Extract unpacks src into dest, dropping the first strip leading path components of each entry.
+	Extract(src, dest string, strip int) error
+}
+
+var (
+	extractorsMu sync.Mutex
+	extractors   []Extractor
+)
+
+// RegisterExtractor adds e to the extractors consulted by extractFile. Extractors are tried in
+// registration order and the first one whose Match returns true for the downloaded file is used;
+// the built-in archiver catch-all is only used when no other registered Extractor matches.
+func RegisterExtractor(e Extractor) {
+	extractorsMu.Lock()
+	defer extractorsMu.Unlock()
+	extractors = append(extractors, e)
+}
+
+func findExtractor(filename string) Extractor {
+	extractorsMu.Lock()
+	defer extractorsMu.Unlock()
+
+	var fallback Extractor
+
+	for _, e := range extractors {
+		// The archiver catch-all matches every name, so it's skipped here and only returned
+		// when no other Extractor, including ones registered after it, matches.
+		if _, ok := e.(*archiverExtractor); ok {
+			fallback = e
+			continue
+		}
+
+		if e.Match(filename) {
+			return e
+		}
+	}
+
+	return fallback
+}
+
+func init() {
+	RegisterExtractor(&tarExtractor{suffixes: []string{".tar.gz", ".tgz"}, decompress: func(r io.Reader) (io.Reader, error) {
+		return gzip.NewReader(r)
+	}})
+	RegisterExtractor(&tarExtractor{suffixes: []string{".tar.bz2", ".tbz2"}, decompress: func(r io.Reader) (io.Reader, error) {
+		return bzip2.NewReader(r), nil
+	}})
+	RegisterExtractor(&tarExtractor{suffixes: []string{".tar.xz", ".txz"}, decompress: func(r io.Reader) (io.Reader, error) {
+		return xz.NewReader(r)
+	}})
+	RegisterExtractor(&tarExtractor{suffixes: []string{".tar.zst", ".tzst"}, decompress: func(r io.Reader) (io.Reader, error) {
+		d, err := zstd.NewReader(r)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return d.IOReadCloser(), nil
+	}})
+	RegisterExtractor(&tarExtractor{suffixes: []string{".tar"}})
+	RegisterExtractor(&zipExtractor{})
+	RegisterExtractor(&gzipExtractor{})
+	// archiverExtractor is a catch-all fallback for formats without a dedicated Extractor above,
+	// e.g. .rar or .7z, kept for backwards compatibility with the previous archiver-only behaviour.
+	RegisterExtractor(&archiverExtractor{})
+}
+
+func stripComponents(name string, strip int) string {
+	if strip <= 0 {
+		return name
+	}
+
+	parts := strings.Split(filepath.ToSlash(name), "/")
+
+	if len(parts) <= strip {
+		return ""
+	}
+
+	return filepath.Join(parts[strip:]...)
+}
+
+// safeJoin joins dest and name, rejecting entries (e.g. "../../etc/passwd") that would resolve
+// outside dest once cleaned - the classic tar-slip/zip-slip path traversal.
+func safeJoin(dest, name string) (string, error) {
+	target := filepath.Join(dest, name)
+
+	rel, err := filepath.Rel(dest, target)
+
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(os.PathSeparator)) {
+		return "", fmt.Errorf("%s: illegal file path escapes destination", name)
+	}
+
+	return target, nil
+}
+
+// validateSymlinkTarget rejects a symlink whose Linkname is absolute or that, resolved relative to
+// the symlink's own location, would point outside dest - otherwise a later entry extracted through
+// the link (e.g. "link/evil") would pass safeJoin lexically but land outside dest on disk.
+func validateSymlinkTarget(dest, target, linkname string) error {
+	if filepath.IsAbs(linkname) {
+		return fmt.Errorf("%s: symlink target %q is absolute", target, linkname)
+	}
+
+	resolved := filepath.Join(filepath.Dir(target), linkname)
+
+	rel, err := filepath.Rel(dest, resolved)
+
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(os.PathSeparator)) {
+		return fmt.Errorf("%s: symlink target %q escapes destination", target, linkname)
+	}
+
+	return nil
+}
+
+func hasAnySuffix(filename string, suffixes []string) bool {
+	lower := strings.ToLower(filename)
+
+	for _, s := range suffixes {
+		if strings.HasSuffix(lower, s) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// tarExtractor extracts tar archives, optionally wrapped in a compression format, stripping
+// leading path components on the fly as entries are read.
+type tarExtractor struct {
+	suffixes   []string
+	decompress func(io.Reader) (io.Reader, error)
+}
+
+func (t *tarExtractor) Match(filename string) bool {
+	return hasAnySuffix(filename, t.suffixes)
+}
+
+func (t *tarExtractor) Extract(src, dest string, strip int) error {
+	file, err := os.Open(src)
+
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	var r io.Reader = file
+
+	if t.decompress != nil {
+		r, err = t.decompress(file)
+
+		if err != nil {
+			return err
+		}
+
+		if closer, ok := r.(io.Closer); ok {
+			defer closer.Close()
+		}
+	}
+
+	tr := tar.NewReader(r)
+
+	for {
+		header, err := tr.Next()
+
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		name := stripComponents(header.Name, strip)
+
+		if name == "" {
+			continue
+		}
+
+		target, err := safeJoin(dest, name)
+
+		if err != nil {
+			return err
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, os.FileMode(header.Mode)); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+
+			out, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, os.FileMode(header.Mode))
+
+			if err != nil {
+				return err
+			}
+
+			_, err = io.Copy(out, tr)
+			out.Close()
+
+			if err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			if err := validateSymlinkTarget(dest, target, header.Linkname); err != nil {
+				return err
+			}
+
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+
+			os.Remove(target)
+
+			if err := os.Symlink(header.Linkname, target); err != nil {
+				return err
+			}
+		case tar.TypeLink:
+			linkName := stripComponents(header.Linkname, strip)
+
+			if linkName == "" {
+				continue
+			}
+
+			linkTarget, err := safeJoin(dest, linkName)
+
+			if err != nil {
+				return err
+			}
+
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+
+			os.Remove(target)
+
+			if err := os.Link(linkTarget, target); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("%s: unsupported tar entry type %q", header.Name, string(header.Typeflag))
+		}
+	}
+}
+
+// zipExtractor extracts zip archives, stripping leading path components on the fly.
+type zipExtractor struct{}
+
+func (z *zipExtractor) Match(filename string) bool {
+	return hasAnySuffix(filename, []string{".zip"})
+}
+
+func (z *zipExtractor) Extract(src, dest string, strip int) error {
+	r, err := zip.OpenReader(src)
+
+	if err != nil {
+		return err
+	}
+
+	defer r.Close()
+
+	for _, f := range r.File {
+		name := stripComponents(f.Name, strip)
+
+		if name == "" {
+			continue
+		}
+
+		target, err := safeJoin(dest, name)
+
+		if err != nil {
+			return err
+		}
+
+		if f.FileInfo().IsDir() {
+			if err := os.MkdirAll(target, f.Mode()); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+
+		rc, err := f.Open()
+
+		if err != nil {
+			return err
+		}
+
+		out, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, f.Mode())
+
+		if err != nil {
+			rc.Close()
+			return err
+		}
+
+		_, err = io.Copy(out, rc)
+		rc.Close()
+		out.Close()
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// gzipExtractor extracts a plain gzip-compressed single-file payload, e.g. a bare "ffmpeg.gz".
+// strip has no effect since there's no directory structure to strip.
+type gzipExtractor struct{}
+
+func (g *gzipExtractor) Match(filename string) bool {
+	return hasAnySuffix(filename, []string{".gz"})
+}
+
+func (g *gzipExtractor) Extract(src, dest string, strip int) error {
+	file, err := os.Open(src)
+
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+
+	if err != nil {
+		return err
+	}
+
+	defer gz.Close()
+
+	name := strings.TrimSuffix(filepath.Base(src), ".gz")
+
+	if err := os.MkdirAll(dest, 0755); err != nil {
+		return err
+	}
+
+	out, err := os.Create(filepath.Join(dest, name))
+
+	if err != nil {
+		return err
+	}
+
+	defer out.Close()
+
+	_, err = io.Copy(out, gz)
+	return err
+}
+
+// archiverExtractor falls back to github.com/mholt/archiver's format auto-detection for anything
+// not handled by a more specific Extractor above.
+type archiverExtractor struct{}
+
+func (a *archiverExtractor) Match(filename string) bool {
+	return true
+}
+
+func (a *archiverExtractor) Extract(src, dest string, strip int) error {
+	err := archiver.Unarchive(src, dest)
+
+	if err != nil {
+		return err
+	}
+
+	if strip == 0 {
+		return nil
+	}
+
+	return stripDir(dest, strip)
+}
+
+func stripDir(dest string, strip int) error {
+	dir := dest
+
+	var dirsToRemove []string
+
+	for i := 0; i < strip; i++ {
+		files, err := ioutil.ReadDir(dir)
+
+		if err != nil {
+			return err
+		}
+
+		for _, v := range files {
+			if v.IsDir() {
+
+				if dir != dest {
+					dirsToRemove = append(dirsToRemove, dir)
+				}
+
+				dir = filepath.Join(dir, v.Name())
+				break
+			}
+		}
+	}
+
+	files, err := ioutil.ReadDir(dir)
+
+	if err != nil {
+		return err
+	}
+
+	for _, v := range files {
+		err := os.Rename(filepath.Join(dir, v.Name()), filepath.Join(dest, v.Name()))
+
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, v := range dirsToRemove {
+		os.RemoveAll(v)
+	}
+
+	return nil
+}
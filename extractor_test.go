@@ -0,0 +1,157 @@
+package binwrapper
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStripComponents(t *testing.T) {
+	assert.Equal(t, "a/b/c", filepath.ToSlash(stripComponents("a/b/c", 0)))
+	assert.Equal(t, "b/c", filepath.ToSlash(stripComponents("a/b/c", 1)))
+	assert.Equal(t, "c", filepath.ToSlash(stripComponents("a/b/c", 2)))
+	assert.Equal(t, "", stripComponents("a/b/c", 3))
+	assert.Equal(t, "", stripComponents("a/b/c", 10))
+}
+
+func TestSafeJoinRejectsTraversal(t *testing.T) {
+	dest := t.TempDir()
+
+	target, err := safeJoin(dest, "file.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(dest, "file.txt"), target)
+
+	_, err = safeJoin(dest, "../../etc/passwd")
+	assert.Error(t, err)
+
+	_, err = safeJoin(dest, "../escape.txt")
+	assert.Error(t, err)
+}
+
+func TestFindExtractorDispatch(t *testing.T) {
+	assert.IsType(t, &tarExtractor{}, findExtractor("archive.tar.gz"))
+	assert.IsType(t, &tarExtractor{}, findExtractor("archive.tgz"))
+	assert.IsType(t, &tarExtractor{}, findExtractor("archive.tar.bz2"))
+	assert.IsType(t, &tarExtractor{}, findExtractor("archive.tar.xz"))
+	assert.IsType(t, &tarExtractor{}, findExtractor("archive.tar.zst"))
+	assert.IsType(t, &tarExtractor{}, findExtractor("archive.tar"))
+	assert.IsType(t, &zipExtractor{}, findExtractor("archive.zip"))
+	assert.IsType(t, &gzipExtractor{}, findExtractor("ffmpeg.gz"))
+	// Anything unrecognised falls through to the archiver catch-all.
+	assert.IsType(t, &archiverExtractor{}, findExtractor("archive.rar"))
+}
+
+func TestTarExtractorRejectsPathTraversal(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "archive.tar")
+
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+	assert.NoError(t, tw.WriteHeader(&tar.Header{Name: "../../evil.txt", Typeflag: tar.TypeReg, Mode: 0644, Size: 4}))
+	_, err := tw.Write([]byte("evil"))
+	assert.NoError(t, err)
+	assert.NoError(t, tw.Close())
+	assert.NoError(t, ioutil.WriteFile(src, buf.Bytes(), 0644))
+
+	dest := filepath.Join(dir, "dest")
+	assert.NoError(t, os.MkdirAll(dest, 0755))
+
+	err = (&tarExtractor{suffixes: []string{".tar"}}).Extract(src, dest, 0)
+	assert.Error(t, err)
+}
+
+func TestTarExtractorRejectsSymlinkEscape(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "absolute.tar")
+
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+	assert.NoError(t, tw.WriteHeader(&tar.Header{Name: "link", Typeflag: tar.TypeSymlink, Linkname: "/etc/cron.d", Mode: 0644}))
+	assert.NoError(t, tw.Close())
+	assert.NoError(t, ioutil.WriteFile(src, buf.Bytes(), 0644))
+
+	dest := filepath.Join(dir, "dest")
+	assert.NoError(t, os.MkdirAll(dest, 0755))
+
+	err := (&tarExtractor{suffixes: []string{".tar"}}).Extract(src, dest, 0)
+	assert.Error(t, err)
+	_, statErr := os.Lstat(filepath.Join(dest, "link"))
+	assert.True(t, os.IsNotExist(statErr))
+
+	src = filepath.Join(dir, "relative.tar")
+	buf = &bytes.Buffer{}
+	tw = tar.NewWriter(buf)
+	assert.NoError(t, tw.WriteHeader(&tar.Header{Name: "link", Typeflag: tar.TypeSymlink, Linkname: "../../outside", Mode: 0644}))
+	assert.NoError(t, tw.Close())
+	assert.NoError(t, ioutil.WriteFile(src, buf.Bytes(), 0644))
+
+	err = (&tarExtractor{suffixes: []string{".tar"}}).Extract(src, dest, 0)
+	assert.Error(t, err)
+	_, statErr = os.Lstat(filepath.Join(dest, "link"))
+	assert.True(t, os.IsNotExist(statErr))
+}
+
+func TestTarExtractorExtractsRegularFileAndSymlink(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "archive.tar.gz")
+
+	buf := &bytes.Buffer{}
+	gw := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gw)
+
+	assert.NoError(t, tw.WriteHeader(&tar.Header{Name: "nested/file.txt", Typeflag: tar.TypeReg, Mode: 0644, Size: 5}))
+	_, err := tw.Write([]byte("hello"))
+	assert.NoError(t, err)
+
+	assert.NoError(t, tw.WriteHeader(&tar.Header{Name: "nested/link.txt", Typeflag: tar.TypeSymlink, Linkname: "file.txt", Mode: 0644}))
+
+	assert.NoError(t, tw.Close())
+	assert.NoError(t, gw.Close())
+	assert.NoError(t, ioutil.WriteFile(src, buf.Bytes(), 0644))
+
+	dest := filepath.Join(dir, "dest")
+	assert.NoError(t, os.MkdirAll(dest, 0755))
+
+	extractor := &tarExtractor{suffixes: []string{".tar.gz", ".tgz"}, decompress: func(r io.Reader) (io.Reader, error) {
+		return gzip.NewReader(r)
+	}}
+
+	err = extractor.Extract(src, dest, 1)
+	assert.NoError(t, err)
+
+	content, err := ioutil.ReadFile(filepath.Join(dest, "file.txt"))
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", string(content))
+
+	target, err := os.Readlink(filepath.Join(dest, "link.txt"))
+	assert.NoError(t, err)
+	assert.Equal(t, "file.txt", target)
+}
+
+func TestZipExtractorRejectsPathTraversal(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "archive.zip")
+
+	buf := &bytes.Buffer{}
+	zw := zip.NewWriter(buf)
+	w, err := zw.Create("../../evil.txt")
+	assert.NoError(t, err)
+	_, err = w.Write([]byte("evil"))
+	assert.NoError(t, err)
+	assert.NoError(t, zw.Close())
+	assert.NoError(t, ioutil.WriteFile(src, buf.Bytes(), 0644))
+
+	dest := filepath.Join(dir, "dest")
+	assert.NoError(t, os.MkdirAll(dest, 0755))
+
+	err = (&zipExtractor{}).Extract(src, dest, 0)
+	assert.Error(t, err)
+}
@@ -0,0 +1,98 @@
+package binwrapper
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"strings"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// minisignPublicKey is a parsed minisign public key file (https://jedisct1.github.io/minisign/)
+type minisignPublicKey struct {
+	keyID     [8]byte
+	publicKey ed25519.PublicKey
+}
+
+// minisignSignature is a parsed minisign signature file. prehashed reflects the signature
+// algorithm: "ED" (the default produced by `minisign -S` since 2022) signs the BLAKE2b-512 hash
+// of the file rather than the file itself; the legacy "Ed" algorithm signs the file directly.
+type minisignSignature struct {
+	keyID     [8]byte
+	signature [ed25519.SignatureSize]byte
+	prehashed bool
+}
+
+// firstNonCommentLine returns the first line of value that isn't blank or a minisign comment line
+func firstNonCommentLine(value string) string {
+	for _, line := range strings.Split(value, "\n") {
+		line = strings.TrimSpace(line)
+
+		if line == "" || strings.HasPrefix(line, "untrusted comment:") || strings.HasPrefix(line, "trusted comment:") {
+			continue
+		}
+
+		return line
+	}
+
+	return ""
+}
+
+func parseMinisignPublicKey(value string) (*minisignPublicKey, error) {
+	raw, err := base64.StdEncoding.DecodeString(firstNonCommentLine(value))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(raw) != 42 {
+		return nil, errors.New("invalid minisign public key")
+	}
+
+	if raw[0] != 'E' || raw[1] != 'd' {
+		return nil, errors.New("unsupported minisign public key algorithm")
+	}
+
+	key := &minisignPublicKey{publicKey: append(ed25519.PublicKey(nil), raw[10:42]...)}
+	copy(key.keyID[:], raw[2:10])
+
+	return key, nil
+}
+
+func parseMinisignSignature(value string) (*minisignSignature, error) {
+	raw, err := base64.StdEncoding.DecodeString(firstNonCommentLine(value))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(raw) != 74 {
+		return nil, errors.New("invalid minisign signature")
+	}
+
+	if raw[0] != 'E' || (raw[1] != 'd' && raw[1] != 'D') {
+		return nil, errors.New("unsupported minisign signature algorithm")
+	}
+
+	sig := &minisignSignature{prehashed: raw[1] == 'D'}
+	copy(sig.keyID[:], raw[2:10])
+	copy(sig.signature[:], raw[10:74])
+
+	return sig, nil
+}
+
+// verify reports whether signature is a valid Ed25519 signature of data (or, for the prehashed
+// "ED" algorithm, of data's BLAKE2b-512 hash) made by publicKey.
+func (k *minisignPublicKey) verify(data []byte, signature *minisignSignature) bool {
+	if k.keyID != signature.keyID {
+		return false
+	}
+
+	if signature.prehashed {
+		hashed := blake2b.Sum512(data)
+		return ed25519.Verify(k.publicKey, hashed[:], signature.signature[:])
+	}
+
+	return ed25519.Verify(k.publicKey, data, signature.signature[:])
+}
@@ -0,0 +1,71 @@
+package binwrapper
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/blake2b"
+)
+
+func encodeMinisignPublicKey(keyID [8]byte, publicKey ed25519.PublicKey) string {
+	raw := append([]byte{'E', 'd'}, keyID[:]...)
+	raw = append(raw, publicKey...)
+	return base64.StdEncoding.EncodeToString(raw)
+}
+
+func encodeMinisignSignature(algorithm string, keyID [8]byte, signature []byte) string {
+	raw := append([]byte(algorithm), keyID[:]...)
+	raw = append(raw, signature...)
+	return base64.StdEncoding.EncodeToString(raw)
+}
+
+func TestVerifySignatureLegacyAlgorithm(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	assert.NoError(t, err)
+
+	var keyID [8]byte
+	copy(keyID[:], []byte("12345678"))
+
+	data := []byte("hello world")
+	signature := ed25519.Sign(privateKey, data)
+
+	key, err := parseMinisignPublicKey(encodeMinisignPublicKey(keyID, publicKey))
+	assert.NoError(t, err)
+
+	sig, err := parseMinisignSignature(encodeMinisignSignature("Ed", keyID, signature))
+	assert.NoError(t, err)
+	assert.False(t, sig.prehashed)
+
+	assert.True(t, key.verify(data, sig))
+	assert.False(t, key.verify([]byte("tampered"), sig))
+}
+
+func TestVerifySignaturePrehashedAlgorithm(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	assert.NoError(t, err)
+
+	var keyID [8]byte
+	copy(keyID[:], []byte("12345678"))
+
+	data := []byte("hello world")
+	hashed := blake2b.Sum512(data)
+	signature := ed25519.Sign(privateKey, hashed[:])
+
+	key, err := parseMinisignPublicKey(encodeMinisignPublicKey(keyID, publicKey))
+	assert.NoError(t, err)
+
+	sig, err := parseMinisignSignature(encodeMinisignSignature("ED", keyID, signature))
+	assert.NoError(t, err)
+	assert.True(t, sig.prehashed)
+
+	assert.True(t, key.verify(data, sig))
+	assert.False(t, key.verify([]byte("tampered"), sig))
+}
+
+func TestParseMinisignSignatureRejectsUnknownAlgorithm(t *testing.T) {
+	var keyID [8]byte
+	_, err := parseMinisignSignature(encodeMinisignSignature("Ex", keyID, make([]byte, ed25519.SignatureSize)))
+	assert.Error(t, err)
+}
@@ -0,0 +1,24 @@
+package binwrapper
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPipeSurfacesWiringError(t *testing.T) {
+	first := NewBinWrapper().ExecPath("cat").SetStdOut(&bytes.Buffer{})
+	second := NewBinWrapper().ExecPath("cat")
+
+	result := first.Pipe(second)
+
+	assert.Same(t, second, result)
+	assert.Error(t, second.pipeErr)
+
+	assert.Equal(t, second.pipeErr, second.Start())
+	assert.Equal(t, second.pipeErr, second.Run())
+
+	second.Reset()
+	assert.Nil(t, second.pipeErr)
+}
@@ -0,0 +1,254 @@
+package binwrapper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+)
+
+// ErrNoUpdateAvailable is returned by SelfUpdate when the currently installed version is already up to date
+var ErrNoUpdateAvailable = errors.New("no update available")
+
+// PlatformSource describes where to download a specific os/arch build and its expected sha256 checksum
+type PlatformSource struct {
+	URL    string `json:"url"`
+	Sha256 string `json:"sha256"`
+}
+
+// VersionManifest describes the latest available version of a binary and where to download it for each platform
+type VersionManifest struct {
+	Version   string                    `json:"version"`
+	Platforms map[string]PlatformSource `json:"platforms"`
+}
+
+func (m *VersionManifest) platform() (PlatformSource, bool) {
+	src, ok := m.Platforms[runtime.GOOS+"/"+runtime.GOARCH]
+	return src, ok
+}
+
+// UpdateChecker fetches and parses a version manifest published at a well-known URL
+type UpdateChecker struct {
+	ManifestURL string
+}
+
+// NewUpdateChecker creates an UpdateChecker for the manifest hosted at manifestURL
+func NewUpdateChecker(manifestURL string) *UpdateChecker {
+	return &UpdateChecker{ManifestURL: manifestURL}
+}
+
+// Fetch downloads and parses the version manifest
+func (c *UpdateChecker) Fetch(ctx context.Context) (*VersionManifest, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.ManifestURL, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if !(resp.StatusCode >= 200 && resp.StatusCode < 400) {
+		return nil, fmt.Errorf("unable to fetch version manifest: %s", resp.Status)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest VersionManifest
+
+	if err := json.Unmarshal(body, &manifest); err != nil {
+		return nil, err
+	}
+
+	return &manifest, nil
+}
+
+// CheckUpdate consults VersionURL and returns the published version if it differs from
+// the version set through Version, or an empty string if already up to date.
+func (b *BinWrapper) CheckUpdate(ctx context.Context) (string, error) {
+	if b.versionURL == "" {
+		return "", errors.New("VersionURL is not set")
+	}
+
+	manifest, err := NewUpdateChecker(b.versionURL).Fetch(ctx)
+
+	if err != nil {
+		return "", err
+	}
+
+	if manifest.Version == b.version {
+		return "", nil
+	}
+
+	return manifest.Version, nil
+}
+
+// SelfUpdate downloads the version published at VersionURL for the current OS/arch, verifies its
+// checksum, moves the currently installed binary into a backup/<version> folder and atomically
+// swaps the new binary into its place. Returns ErrNoUpdateAvailable if already up to date.
+func (b *BinWrapper) SelfUpdate(ctx context.Context) error {
+	b.updateMu.Lock()
+	defer b.updateMu.Unlock()
+
+	if b.versionURL == "" {
+		return errors.New("VersionURL is not set")
+	}
+
+	if b.execPath == "" {
+		return errors.New("ExecPath is not set")
+	}
+
+	manifest, err := NewUpdateChecker(b.versionURL).Fetch(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	if manifest.Version == b.version {
+		return ErrNoUpdateAvailable
+	}
+
+	platform, ok := manifest.platform()
+
+	if !ok {
+		return fmt.Errorf("no download available for %s/%s", runtime.GOOS, runtime.GOARCH)
+	}
+
+	if b.dest == "" {
+		b.dest = "."
+	}
+
+	updateDir := filepath.Join(b.dest, "update")
+
+	err = os.RemoveAll(updateDir)
+
+	if err != nil {
+		return err
+	}
+
+	staging := NewBinWrapper().
+		Dest(updateDir).
+		Strip(b.strip).
+		Src(NewSrc().URL(platform.URL).Sha256(platform.Sha256))
+
+	file, err := staging.downloadFile(osFilterObj(staging.src))
+
+	if err != nil {
+		return err
+	}
+
+	err = staging.extractFile(file)
+
+	if err != nil {
+		return err
+	}
+
+	stagedExec := filepath.Join(updateDir, b.execPath)
+
+	if _, err := os.Stat(stagedExec); err != nil {
+		return fmt.Errorf("update archive doesn't contain %s: %w", b.execPath, err)
+	}
+
+	liveExec := filepath.Join(b.dest, b.execPath)
+
+	if _, err := os.Stat(liveExec); err == nil {
+		// b.version may be empty if the caller never called Version; Rollback only scans
+		// directories under backup/, so an empty key would collapse to dest/backup/<execPath>
+		// (a file, not a directory) and make the backup unreachable.
+		backupKey := b.version
+
+		if backupKey == "" {
+			backupKey = "unversioned-" + time.Now().UTC().Format("20060102150405")
+		}
+
+		backupDir := filepath.Join(b.dest, "backup", backupKey)
+
+		err = os.MkdirAll(backupDir, 0755)
+
+		if err != nil {
+			return err
+		}
+
+		err = os.Rename(liveExec, filepath.Join(backupDir, b.execPath))
+
+		if err != nil {
+			return err
+		}
+	}
+
+	err = os.Rename(stagedExec, liveExec)
+
+	if err != nil {
+		return err
+	}
+
+	os.RemoveAll(updateDir)
+
+	b.version = manifest.Version
+
+	return nil
+}
+
+// Rollback restores the most recently backed up version over the live binary.
+func (b *BinWrapper) Rollback() error {
+	b.updateMu.Lock()
+	defer b.updateMu.Unlock()
+
+	if b.execPath == "" {
+		return errors.New("ExecPath is not set")
+	}
+
+	backupRoot := filepath.Join(b.dest, "backup")
+
+	entries, err := ioutil.ReadDir(backupRoot)
+
+	if err != nil {
+		return err
+	}
+
+	var latest os.FileInfo
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		if latest == nil || entry.ModTime().After(latest.ModTime()) {
+			latest = entry
+		}
+	}
+
+	if latest == nil {
+		return errors.New("no backup to roll back to")
+	}
+
+	backedUpExec := filepath.Join(backupRoot, latest.Name(), b.execPath)
+	liveExec := filepath.Join(b.dest, b.execPath)
+
+	err = os.Rename(backedUpExec, liveExec)
+
+	if err != nil {
+		return err
+	}
+
+	os.RemoveAll(filepath.Join(backupRoot, latest.Name()))
+
+	b.version = latest.Name()
+
+	return nil
+}
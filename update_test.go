@@ -0,0 +1,77 @@
+package binwrapper
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func buildTarGz(t *testing.T, entries map[string]string) ([]byte, string) {
+	buf := &bytes.Buffer{}
+	gw := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gw)
+
+	for name, content := range entries {
+		assert.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Typeflag: tar.TypeReg, Mode: 0755, Size: int64(len(content))}))
+		_, err := tw.Write([]byte(content))
+		assert.NoError(t, err)
+	}
+
+	assert.NoError(t, tw.Close())
+	assert.NoError(t, gw.Close())
+
+	sum := sha256.Sum256(buf.Bytes())
+	return buf.Bytes(), hex.EncodeToString(sum[:])
+}
+
+func TestSelfUpdatePropagatesStrip(t *testing.T) {
+	archive, checksum := buildTarGz(t, map[string]string{"mytool-1.0/mytool": "binary"})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/mytool.tar.gz", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(archive)
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	manifest := &VersionManifest{
+		Version: "1.0",
+		Platforms: map[string]PlatformSource{
+			runtime.GOOS + "/" + runtime.GOARCH: {URL: server.URL + "/mytool.tar.gz", Sha256: checksum},
+		},
+	}
+
+	mux.HandleFunc("/manifest.json", func(w http.ResponseWriter, r *http.Request) {
+		body, err := json.Marshal(manifest)
+		assert.NoError(t, err)
+		w.Write(body)
+	})
+
+	dest := t.TempDir()
+
+	b := NewBinWrapper().
+		Dest(dest).
+		ExecPath("mytool").
+		Strip(1).
+		VersionURL(server.URL + "/manifest.json")
+
+	err := b.SelfUpdate(context.Background())
+	assert.NoError(t, err)
+
+	content, err := os.ReadFile(filepath.Join(dest, "mytool"))
+	assert.NoError(t, err)
+	assert.Equal(t, "binary", string(content))
+}